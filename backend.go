@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// Backend describes one target instance this CLI can talk to: its base
+// URL, the path of each endpoint, one auth convention for all of them,
+// and the response field name each endpoint uses for the created
+// resource's URL. This lets
+// one binary target keiran.cc, a self-hosted pasty, a rushlink instance,
+// or a linx-server without hard-coding any of their conventions.
+type Backend struct {
+	BaseURL        string `toml:"base_url"`
+	UploadPath     string `toml:"upload_path,omitempty"`
+	PastePath      string `toml:"paste_path,omitempty"`
+	ShortenPath    string `toml:"shorten_path,omitempty"`
+	UploadURLField string `toml:"upload_url_field,omitempty"`
+	PasteURLField  string `toml:"paste_url_field,omitempty"`
+	ShortURLField  string `toml:"short_url_field,omitempty"`
+	// AuthType selects how setAuthHeader authenticates requests to this
+	// backend: "bearer" (default) sends "Authorization: Bearer <key>";
+	// "none" sends no auth header at all, for anonymous instances.
+	AuthType string `toml:"auth_type,omitempty"`
+	// APIKey, when set, overrides the global key (KEIRAN_API_KEY / `login`)
+	// for this backend only, so a profile can hold its own credential.
+	APIKey string `toml:"api_key,omitempty"`
+}
+
+// defaultBackend is the built-in keiran.cc profile, preserved as the
+// zero-config default so existing usage keeps working unchanged.
+func defaultBackend() *Backend {
+	return &Backend{
+		BaseURL:        "https://keiran.cc/api",
+		UploadPath:     "/upload",
+		PastePath:      "/pastes",
+		ShortenPath:    "/shorten",
+		UploadURLField: "imageUrl",
+		PasteURLField:  "url",
+		ShortURLField:  "shortUrl",
+		AuthType:       "bearer",
+	}
+}
+
+func (b *Backend) uploadURL() string  { return b.BaseURL + orDefault(b.UploadPath, "/upload") }
+func (b *Backend) pasteURL() string   { return b.BaseURL + orDefault(b.PastePath, "/pastes") }
+func (b *Backend) shortenURL() string { return b.BaseURL + orDefault(b.ShortenPath, "/shorten") }
+
+func (b *Backend) uploadURLField() string { return orDefault(b.UploadURLField, "imageUrl") }
+func (b *Backend) pasteURLField() string  { return orDefault(b.PasteURLField, "url") }
+func (b *Backend) shortURLField() string  { return orDefault(b.ShortURLField, "shortUrl") }
+
+func (b *Backend) authType() string { return orDefault(b.AuthType, "bearer") }
+
+// authKey resolves the credential to authenticate with: this backend's
+// own APIKey when set, otherwise the global key from KEIRAN_API_KEY or
+// `keirancli login`.
+func (b *Backend) authKey() string {
+	if b.APIKey != "" {
+		return b.APIKey
+	}
+	return apiKey()
+}
+
+// setAuthHeader attaches this backend's auth convention to req. Profiles
+// with auth_type = "none" (e.g. an anonymous linx-server) send no header
+// at all; otherwise the resolved key is sent as a Bearer token.
+func (b *Backend) setAuthHeader(req *http.Request) {
+	if b.authType() == "none" {
+		return
+	}
+	if key := b.authKey(); key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+var (
+	serverFlag string
+	urlFlag    string
+)
+
+func registerBackendFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&serverFlag, "server", "", "Named profile to use (see `keirancli profiles list`)")
+	cmd.PersistentFlags().StringVar(&urlFlag, "url", "", "One-off base URL override, bypassing profiles")
+}
+
+// resolveBackend picks the Backend to use for this invocation: an --url
+// override wins outright, then --server by name, then the profile marked
+// current in the config file, and finally the built-in default.
+func resolveBackend() (*Backend, error) {
+	if urlFlag != "" {
+		b := defaultBackend()
+		b.BaseURL = urlFlag
+		return b, nil
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	name := serverFlag
+	if name == "" {
+		name = cfg.CurrentProfile
+	}
+	if name == "" {
+		return defaultBackend(), nil
+	}
+
+	backend, ok := cfg.Profiles[name]
+	if !ok {
+		// "default" names the built-in profile that `profiles list` shows.
+		if name == "default" {
+			return defaultBackend(), nil
+		}
+		return nil, fmt.Errorf("unknown profile %q; see `keirancli profiles list`", name)
+	}
+	return &backend, nil
+}
+
+var profilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "Manage named backend profiles",
+}
+
+var profileAddURL string
+var profileAddUploadPath string
+var profileAddPastePath string
+var profileAddShortenPath string
+var profileAddUploadURLField string
+var profileAddPasteURLField string
+var profileAddShortURLField string
+var profileAddAuthType string
+var profileAddAPIKey string
+
+var profilesAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a named backend profile",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if profileAddURL == "" {
+			fmt.Println("--url is required")
+			return
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			fmt.Printf("Failed to load config: %v\n", err)
+			return
+		}
+		if cfg.Profiles == nil {
+			cfg.Profiles = map[string]Backend{}
+		}
+
+		cfg.Profiles[args[0]] = Backend{
+			BaseURL:        profileAddURL,
+			UploadPath:     profileAddUploadPath,
+			PastePath:      profileAddPastePath,
+			ShortenPath:    profileAddShortenPath,
+			UploadURLField: profileAddUploadURLField,
+			PasteURLField:  profileAddPasteURLField,
+			ShortURLField:  profileAddShortURLField,
+			AuthType:       profileAddAuthType,
+			APIKey:         profileAddAPIKey,
+		}
+
+		if err := saveConfig(cfg); err != nil {
+			fmt.Printf("Failed to save config: %v\n", err)
+			return
+		}
+		fmt.Printf("Added profile %q.\n", args[0])
+	},
+}
+
+var profilesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured backend profiles",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := loadConfig()
+		if err != nil {
+			fmt.Printf("Failed to load config: %v\n", err)
+			return
+		}
+
+		names := make([]string, 0, len(cfg.Profiles)+1)
+		names = append(names, "default")
+		for name := range cfg.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			backend := defaultBackend()
+			if b, ok := cfg.Profiles[name]; ok {
+				backend = &b
+			}
+
+			marker := " "
+			if name == cfg.CurrentProfile || (cfg.CurrentProfile == "" && name == "default") {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\t%s\n", marker, name, backend.BaseURL)
+		}
+	},
+}
+
+var profilesUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Set the default profile for future commands",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		cfg, err := loadConfig()
+		if err != nil {
+			fmt.Printf("Failed to load config: %v\n", err)
+			return
+		}
+
+		// "default" is the built-in profile, not an entry in
+		// cfg.Profiles, so it's stored as the empty sentinel that
+		// resolveBackend already treats as "use the default".
+		if name == "default" {
+			name = ""
+		} else if _, ok := cfg.Profiles[name]; !ok {
+			fmt.Printf("Unknown profile %q; see `keirancli profiles list`\n", name)
+			return
+		}
+
+		cfg.CurrentProfile = name
+		if err := saveConfig(cfg); err != nil {
+			fmt.Printf("Failed to save config: %v\n", err)
+			return
+		}
+		fmt.Printf("Now using profile %q.\n", args[0])
+	},
+}
+
+func init() {
+	profilesAddCmd.Flags().StringVar(&profileAddURL, "url", "", "Base URL of the backend's API")
+	profilesAddCmd.Flags().StringVar(&profileAddUploadPath, "upload-path", "", "Path of the upload endpoint (default /upload)")
+	profilesAddCmd.Flags().StringVar(&profileAddPastePath, "paste-path", "", "Path of the paste endpoint (default /pastes)")
+	profilesAddCmd.Flags().StringVar(&profileAddShortenPath, "shorten-path", "", "Path of the shorten endpoint (default /shorten)")
+	profilesAddCmd.Flags().StringVar(&profileAddUploadURLField, "upload-url-field", "", "Response field holding the uploaded file's URL (default imageUrl)")
+	profilesAddCmd.Flags().StringVar(&profileAddPasteURLField, "paste-url-field", "", "Response field holding the created paste's URL (default url)")
+	profilesAddCmd.Flags().StringVar(&profileAddShortURLField, "short-url-field", "", "Response field holding the shortened URL (default shortUrl)")
+	profilesAddCmd.Flags().StringVar(&profileAddAuthType, "auth-type", "", "Auth convention for this backend: bearer (default) or none")
+	profilesAddCmd.Flags().StringVar(&profileAddAPIKey, "api-key", "", "Credential to send for this profile only (defaults to the global key)")
+
+	profilesCmd.AddCommand(profilesAddCmd, profilesListCmd, profilesUseCmd)
+}
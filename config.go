@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+)
+
+const apiKeyEnvVar = "KEIRAN_API_KEY"
+
+// Config holds the on-disk CLI configuration stored at configPath().
+type Config struct {
+	APIKey         string             `toml:"api_key"`
+	CurrentProfile string             `toml:"current_profile,omitempty"`
+	Profiles       map[string]Backend `toml:"profiles,omitempty"`
+}
+
+// configDir returns ~/.config/keirancli. It does not create the
+// directory; saveConfig does that when it writes.
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "keirancli"), nil
+}
+
+func configPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// loadConfig reads the config file, returning a zero-value Config if it
+// doesn't exist yet.
+func loadConfig() (*Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{}, nil
+		}
+		return nil, err
+	}
+
+	var cfg Config
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config at %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// saveConfig writes cfg to disk with 0600 perms, creating the config
+// directory if needed.
+func saveConfig(cfg *Config) error {
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := toml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// apiKey resolves the API token to use for authenticated requests. The
+// KEIRAN_API_KEY environment variable takes precedence over the config
+// file, matching the usual env-override-config convention.
+func apiKey() string {
+	if key := os.Getenv(apiKeyEnvVar); key != "" {
+		return key
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return ""
+	}
+	return cfg.APIKey
+}
+
+func runLogin(cmd *cobra.Command, args []string) {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Enter your API token: ")
+	token, _ := reader.ReadString('\n')
+	token = strings.TrimSpace(token)
+	if token == "" {
+		log.Fatal("No token provided")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.APIKey = token
+
+	if err := saveConfig(cfg); err != nil {
+		log.Fatalf("Failed to save config: %v", err)
+	}
+
+	path, _ := configPath()
+	fmt.Printf("Saved API token to %s\n", path)
+}
+
+// runLogout clears the saved API token but leaves other config state
+// (profiles, current profile) intact.
+func runLogout(cmd *cobra.Command, args []string) {
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	if cfg.APIKey == "" {
+		fmt.Println("Already logged out.")
+		return
+	}
+
+	cfg.APIKey = ""
+	if err := saveConfig(cfg); err != nil {
+		log.Fatalf("Failed to save config: %v", err)
+	}
+
+	fmt.Println("Logged out.")
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var deleteTokenFlag string
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <url>",
+	Short: "Delete a previously created paste, upload or short URL, given its full URL (a bare id is not resolved)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		urlOrID := args[0]
+		entry, err := resolveToken(urlOrID, deleteTokenFlag)
+		if err != nil {
+			log.Fatalf("Delete failed: %v", err)
+		}
+
+		backend, err := resolveBackend()
+		if err != nil {
+			log.Fatalf("Delete failed: %v", err)
+		}
+
+		var delErr error
+		switch entry.Kind {
+		case "upload":
+			delErr = backend.deleteUpload(urlOrID, entry.Token)
+		case "paste":
+			delErr = backend.deletePaste(urlOrID, entry.Token)
+		case "shorten":
+			delErr = backend.deleteShort(urlOrID, entry.Token)
+		default:
+			// No stored kind (e.g. --token was passed explicitly). All
+			// three delete helpers send the same request, so deletePaste
+			// is used.
+			delErr = backend.deletePaste(urlOrID, entry.Token)
+		}
+		if delErr != nil {
+			log.Fatalf("Delete failed: %v", delErr)
+		}
+
+		if err := forgetToken(urlOrID); err != nil {
+			fmt.Printf("Warning: failed to update local token index: %v\n", err)
+		}
+		fmt.Println("Deleted.")
+	},
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List past uploads, pastes and shortens tracked locally",
+	Run: func(cmd *cobra.Command, args []string) {
+		idx, err := loadTokenIndex()
+		if err != nil {
+			log.Fatalf("Failed to load token index: %v", err)
+		}
+		if len(idx) == 0 {
+			fmt.Println("No tracked resources yet.")
+			return
+		}
+
+		urls := make([]string, 0, len(idx))
+		for url := range idx {
+			urls = append(urls, url)
+		}
+		sort.Strings(urls)
+
+		for _, url := range urls {
+			entry := idx[url]
+			fmt.Printf("%s\t%s\t%s\n", entry.Kind, url, entry.CreatedAt.Format(time.RFC3339))
+		}
+	},
+}
+
+var pruneOlderThan string
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove stale entries from the local token index",
+	Run: func(cmd *cobra.Command, args []string) {
+		maxAge, err := parseDurationWithDays(pruneOlderThan)
+		if err != nil {
+			log.Fatalf("Invalid --older-than value: %v", err)
+		}
+
+		idx, err := loadTokenIndex()
+		if err != nil {
+			log.Fatalf("Failed to load token index: %v", err)
+		}
+
+		cutoff := time.Now().Add(-maxAge)
+		removed := 0
+		for url, entry := range idx {
+			if entry.CreatedAt.Before(cutoff) {
+				delete(idx, url)
+				removed++
+			}
+		}
+
+		if err := saveTokenIndex(idx); err != nil {
+			log.Fatalf("Failed to save token index: %v", err)
+		}
+		fmt.Printf("Pruned %d entr(ies) older than %s.\n", removed, pruneOlderThan)
+	},
+}
+
+func init() {
+	deleteCmd.Flags().StringVar(&deleteTokenFlag, "token", "", "Delete token to use instead of the one stored locally")
+	pruneCmd.Flags().StringVar(&pruneOlderThan, "older-than", "30d", "Remove entries older than this duration (e.g. 30d, 72h)")
+}
+
+func (b *Backend) deleteRequest(url, token string) error {
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("X-Delete-Token", token)
+	}
+	b.setAuthHeader(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("delete failed: %s", resp.Status)
+	}
+	return nil
+}
+
+func (b *Backend) deletePaste(url, token string) error {
+	return b.deleteRequest(url, token)
+}
+
+func (b *Backend) deleteUpload(url, token string) error {
+	return b.deleteRequest(url, token)
+}
+
+func (b *Backend) deleteShort(url, token string) error {
+	return b.deleteRequest(url, token)
+}
+
+// extractDeleteToken pulls the server-assigned delete token out of a
+// create/upload response, matching either the "deleteToken" or
+// "modificationToken" field name (the pasty convention).
+func extractDeleteToken(result map[string]string) string {
+	if tok, ok := result["deleteToken"]; ok {
+		return tok
+	}
+	return result["modificationToken"]
+}
+
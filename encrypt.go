@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"keirancli/internal/crypto"
+)
+
+const saltSize = 16
+
+// resolveEncryptionKey returns the AES key to encrypt with: derived from
+// --password via scrypt when given, otherwise a fresh random key. Either
+// way the raw key (not the password) ends up base64-encoded in the
+// resulting URL fragment, so fetch never needs the password back.
+func resolveEncryptionKey(password string) ([]byte, error) {
+	if password == "" {
+		return crypto.GenerateKey()
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return crypto.DeriveKey(password, salt)
+}
+
+// encryptBytes encrypts data under key and returns it base64-encoded, so
+// the ciphertext is safe to embed in a JSON paste body or upload as a
+// plain-text file.
+func encryptBytes(data, key []byte) (string, error) {
+	r, err := crypto.EncryptStream(bytes.NewReader(data), key)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// withKeyFragment appends the base64-encoded key to rawURL as a URL
+// fragment (`#k=...`), which the server never sees.
+func withKeyFragment(rawURL string, key []byte) string {
+	return rawURL + "#k=" + base64.RawURLEncoding.EncodeToString(key)
+}
+
+// splitKeyFragment separates a shared URL into its base URL and the
+// encryption key carried in its fragment.
+func splitKeyFragment(rawURL string) (string, []byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fragment := u.Fragment
+	u.Fragment = ""
+
+	const prefix = "k="
+	if len(fragment) <= len(prefix) || fragment[:len(prefix)] != prefix {
+		return "", nil, fmt.Errorf("URL has no encryption key fragment")
+	}
+
+	key, err := base64.RawURLEncoding.DecodeString(fragment[len(prefix):])
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid key fragment: %w", err)
+	}
+
+	return u.String(), key, nil
+}
+
+var fetchOutput string
+
+var fetchCmd = &cobra.Command{
+	Use:   "fetch <url>",
+	Short: "Download and decrypt an --encrypt'd paste or upload",
+	Args:  cobra.ExactArgs(1),
+	Run:   runFetch,
+}
+
+func init() {
+	fetchCmd.Flags().StringVar(&fetchOutput, "output", "", "Write decrypted content to this file instead of stdout")
+}
+
+func runFetch(cmd *cobra.Command, args []string) {
+	baseURL, key, err := splitKeyFragment(args[0])
+	if err != nil {
+		log.Fatalf("Fetch failed: %v", err)
+	}
+
+	resp, err := http.Get(baseURL)
+	if err != nil {
+		log.Fatalf("Fetch failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("Fetch failed: %s", resp.Status)
+	}
+
+	encoded, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Fatalf("Fetch failed: %v", err)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encoded)))
+	if err != nil {
+		log.Fatalf("Fetch failed: content is not encrypted or is corrupt: %v", err)
+	}
+
+	plaintext, err := crypto.DecryptStream(bytes.NewReader(ciphertext), key)
+	if err != nil {
+		log.Fatalf("Decryption failed: %v", err)
+	}
+
+	if fetchOutput == "" {
+		io.Copy(os.Stdout, plaintext)
+		return
+	}
+
+	out, err := os.Create(fetchOutput)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", fetchOutput, err)
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, plaintext); err != nil {
+		log.Fatalf("Failed to write %s: %v", fetchOutput, err)
+	}
+	fmt.Printf("Decrypted content written to %s\n", fetchOutput)
+}
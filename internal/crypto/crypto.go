@@ -0,0 +1,101 @@
+// Package crypto implements the client-side encryption used for
+// zero-knowledge paste/upload sharing: content is sealed with AES-256-GCM
+// before it ever leaves the machine, and the key travels separately from
+// the ciphertext (typically as a URL fragment, which servers never see).
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// KeySize is the size in bytes of an AES-256 key.
+const KeySize = 32
+
+const (
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+)
+
+// GenerateKey returns a random AES-256 key.
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// DeriveKey derives an AES-256 key from a password and salt using scrypt,
+// for the --password path. The same password and salt always yield the
+// same key.
+func DeriveKey(password string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, KeySize)
+}
+
+// EncryptStream reads all of r, seals it with AES-256-GCM under a fresh
+// random nonce, and returns the nonce-prefixed ciphertext as a Reader.
+// GCM's authentication tag covers the whole message, so despite the name
+// this buffers r in memory rather than encrypting incrementally -
+// acceptable for the paste/upload sizes this CLI deals with.
+func EncryptStream(r io.Reader, key []byte) (io.Reader, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
+	return bytes.NewReader(ciphertext), nil
+}
+
+// DecryptStream is the inverse of EncryptStream: it reads a
+// nonce-prefixed ciphertext and returns the decrypted plaintext as a
+// Reader.
+func DecryptStream(r io.Reader, key []byte) (io.Reader, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// extToLanguage maps common file extensions to the language names the
+// paste API expects, used to auto-detect --language from --file when it
+// isn't given explicitly.
+var extToLanguage = map[string]string{
+	".go":   "go",
+	".py":   "python",
+	".js":   "javascript",
+	".ts":   "typescript",
+	".rb":   "ruby",
+	".rs":   "rust",
+	".java": "java",
+	".c":    "c",
+	".h":    "c",
+	".cpp":  "cpp",
+	".cc":   "cpp",
+	".cs":   "csharp",
+	".php":  "php",
+	".sh":   "bash",
+	".yml":  "yaml",
+	".yaml": "yaml",
+	".json": "json",
+	".md":   "markdown",
+	".sql":  "sql",
+	".html": "html",
+	".css":  "css",
+}
+
+func languageFromExt(path string) string {
+	return extToLanguage[strings.ToLower(filepath.Ext(path))]
+}
+
+var (
+	pasteTitle       string
+	pasteDescription string
+	pasteLanguage    string
+	pasteExpiration  string
+	pasteDomain      string
+	pasteFile        string
+	pasteStdin       bool
+	pasteEncrypt     bool
+	pastePassword    string
+)
+
+var pasteCmd = &cobra.Command{
+	Use:   "paste",
+	Short: "Create a new paste",
+	Run:   runPaste,
+}
+
+func init() {
+	pasteCmd.Flags().StringVar(&pasteTitle, "title", "", "Title of the paste")
+	pasteCmd.Flags().StringVar(&pasteDescription, "description", "", "Description of the paste (optional)")
+	pasteCmd.Flags().StringVar(&pasteLanguage, "language", "", "Language of the paste (auto-detected from --file when omitted)")
+	pasteCmd.Flags().StringVar(&pasteExpiration, "expiration", "", "Expiration time of the paste (optional)")
+	pasteCmd.Flags().StringVar(&pasteDomain, "domain", "", "Domain to create the paste on (optional)")
+	pasteCmd.Flags().StringVar(&pasteFile, "file", "", "Read paste content from this file instead of prompting")
+	pasteCmd.Flags().BoolVar(&pasteStdin, "stdin", false, "Read paste content from stdin instead of prompting")
+	pasteCmd.Flags().BoolVar(&pasteEncrypt, "encrypt", false, "Encrypt content locally before uploading; the server only sees ciphertext")
+	pasteCmd.Flags().StringVar(&pastePassword, "password", "", "Derive the encryption key from this password instead of generating one (implies --encrypt)")
+}
+
+// runPaste reads the paste content from --file, --stdin, a piped stdin,
+// or interactive prompts (in that order of preference), so the command
+// works both as a Unix filter and as an interactive tool.
+func runPaste(cmd *cobra.Command, args []string) {
+	title, description, content, language, expiration, domain := pasteTitle, pasteDescription, "", pasteLanguage, pasteExpiration, pasteDomain
+
+	switch {
+	case pasteFile != "":
+		data, readErr := os.ReadFile(pasteFile)
+		if readErr != nil {
+			log.Fatalf("Failed to read %s: %v", pasteFile, readErr)
+		}
+		content = strings.TrimSpace(string(data))
+		if language == "" {
+			language = languageFromExt(pasteFile)
+		}
+	case pasteStdin || stdinIsPiped():
+		data, readErr := io.ReadAll(os.Stdin)
+		if readErr != nil {
+			log.Fatalf("Failed to read stdin: %v", readErr)
+		}
+		content = strings.TrimSpace(string(data))
+	default:
+		if anyPasteFlagSet(cmd) {
+			title, description, content, language, expiration, domain =
+				promptForMissingPasteFields(title, description, content, language, expiration, domain)
+		} else {
+			title, description, content, language, expiration, domain = promptForPaste()
+		}
+	}
+
+	if content == "" {
+		log.Fatal("No paste content provided")
+	}
+
+	var key []byte
+	if pasteEncrypt || pastePassword != "" {
+		var keyErr error
+		key, keyErr = resolveEncryptionKey(pastePassword)
+		if keyErr != nil {
+			log.Fatalf("Failed to prepare encryption key: %v", keyErr)
+		}
+
+		encrypted, encErr := encryptBytes([]byte(content), key)
+		if encErr != nil {
+			log.Fatalf("Encryption failed: %v", encErr)
+		}
+		content = encrypted
+	}
+
+	backend, err := resolveBackend()
+	if err != nil {
+		log.Fatalf("Paste creation failed: %v", err)
+	}
+
+	pasteUrl, err := backend.createPaste(title, description, content, language, expiration, domain)
+	if err != nil {
+		log.Fatalf("Paste creation failed: %v", err)
+	}
+
+	if key != nil {
+		pasteUrl = withKeyFragment(pasteUrl, key)
+	}
+
+	fmt.Printf("Paste created successfully. Paste URL: %s\n", pasteUrl)
+	copyToClipboard(pasteUrl)
+	fmt.Println("Paste URL copied to clipboard.")
+}
+
+// stdinIsPiped reports whether stdin is not a terminal, i.e. content is
+// being piped in (e.g. `cat foo.go | keirancli paste`).
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return (info.Mode() & os.ModeCharDevice) == 0
+}
+
+func promptForPaste() (title, description, content, language, expirationTime, domain string) {
+	return promptForMissingPasteFields("", "", "", "", "", "")
+}
+
+// pasteFlagNames lists the paste flags that, when explicitly set, should
+// suppress full interactive prompting in favor of filling in just the
+// fields left blank.
+var pasteFlagNames = []string{"title", "description", "language", "expiration", "domain"}
+
+// anyPasteFlagSet reports whether the user explicitly passed any of the
+// paste content flags, as opposed to relying on their zero values.
+func anyPasteFlagSet(cmd *cobra.Command) bool {
+	for _, name := range pasteFlagNames {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// promptForMissingPasteFields prompts only for the fields that are still
+// empty, so flags explicitly passed by the user are never overwritten.
+func promptForMissingPasteFields(title, description, content, language, expirationTime, domain string) (string, string, string, string, string, string) {
+	reader := bufio.NewReader(os.Stdin)
+
+	title = promptIfEmpty(reader, "Enter the title of the paste: ", title)
+	description = promptIfEmpty(reader, "Enter the description of the paste (optional): ", description)
+	content = promptIfEmpty(reader, "Enter the content of the paste: ", content)
+	language = promptIfEmpty(reader, "Enter the language of the paste: ", language)
+	expirationTime = promptIfEmpty(reader, "Enter the expiration time of the paste (optional): ", expirationTime)
+	domain = promptIfEmpty(reader, "Enter the domain of the paste (optional): ", domain)
+
+	return title, description, content, language, expirationTime, domain
+}
+
+// promptIfEmpty returns current unchanged if it's already set, otherwise
+// prompts for it on reader.
+func promptIfEmpty(reader *bufio.Reader, prompt, current string) string {
+	if current != "" {
+		return current
+	}
+	fmt.Print(prompt)
+	line, _ := reader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func (b *Backend) createPaste(title, description, content, language, expirationTime, domain string) (string, error) {
+	data := map[string]string{
+		"title":          title,
+		"description":    description,
+		"content":        content,
+		"language":       language,
+		"expirationTime": expirationTime,
+		"domain":         domain,
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", b.pasteURL(), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	b.setAuthHeader(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to create paste: %s", resp.Status)
+	}
+
+	var result map[string]string
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return "", err
+	}
+
+	pasteUrl, exists := result[b.pasteURLField()]
+	if !exists {
+		return "", fmt.Errorf("%s not found in response", b.pasteURLField())
+	}
+
+	if err := recordToken(pasteUrl, "paste", extractDeleteToken(result)); err != nil {
+		fmt.Printf("Warning: failed to save delete token: %v\n", err)
+	}
+
+	return pasteUrl, nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tokenEntry records the delete/modification token returned for a single
+// created resource, so it can be deleted later without the user having
+// to keep the token around themselves.
+type tokenEntry struct {
+	Kind      string    `json:"kind"` // "upload", "paste" or "shorten"
+	Token     string    `json:"token"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// tokenIndex maps a resource URL to the entry describing how to delete it.
+type tokenIndex map[string]tokenEntry
+
+// dataDir returns ~/.local/share/keirancli (honoring $XDG_DATA_HOME).
+// It does not create the directory; saveTokenIndex does that when it
+// writes.
+func dataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "keirancli"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "keirancli"), nil
+}
+
+func tokensPath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tokens.json"), nil
+}
+
+// loadTokenIndex reads the token index, returning an empty index if it
+// doesn't exist yet.
+func loadTokenIndex() (tokenIndex, error) {
+	path, err := tokensPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tokenIndex{}, nil
+		}
+		return nil, err
+	}
+
+	idx := tokenIndex{}
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse token index at %s: %w", path, err)
+	}
+	return idx, nil
+}
+
+func saveTokenIndex(idx tokenIndex) error {
+	dir, err := dataDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path, err := tokensPath()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// stripURLFragment drops any #fragment from rawURL, such as the #k=...
+// encryption key encrypted paste/upload URLs carry, so the token index is
+// always keyed by the same bare resource URL the server itself returned.
+func stripURLFragment(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Fragment == "" {
+		return rawURL
+	}
+	u.Fragment = ""
+	return u.String()
+}
+
+// recordToken persists the delete token for a newly created resource.
+// Failures to persist are logged by the caller, not fatal to the
+// create/upload flow itself.
+func recordToken(resourceURL, kind, token string) error {
+	if token == "" {
+		return nil
+	}
+
+	idx, err := loadTokenIndex()
+	if err != nil {
+		return err
+	}
+	idx[stripURLFragment(resourceURL)] = tokenEntry{Kind: kind, Token: token, CreatedAt: time.Now()}
+	return saveTokenIndex(idx)
+}
+
+// resolveToken looks up the delete token for urlOrID, preferring an
+// explicit override. urlOrID is stripped of any #k=... fragment first, so
+// it works whether the caller pastes the bare URL or the full shareable
+// URL an --encrypt'd paste/upload was printed with.
+func resolveToken(urlOrID, override string) (tokenEntry, error) {
+	if override != "" {
+		return tokenEntry{Token: override}, nil
+	}
+
+	urlOrID = stripURLFragment(urlOrID)
+
+	idx, err := loadTokenIndex()
+	if err != nil {
+		return tokenEntry{}, err
+	}
+	entry, ok := idx[urlOrID]
+	if !ok {
+		return tokenEntry{}, fmt.Errorf("no stored delete token for %q; pass --token", urlOrID)
+	}
+	return entry, nil
+}
+
+func forgetToken(resourceURL string) error {
+	idx, err := loadTokenIndex()
+	if err != nil {
+		return err
+	}
+	delete(idx, stripURLFragment(resourceURL))
+	return saveTokenIndex(idx)
+}
+
+// parseDurationWithDays extends time.ParseDuration with a "d" (day) unit,
+// e.g. "30d", since the stdlib parser has no notion of a day.
+func parseDurationWithDays(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
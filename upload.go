@@ -0,0 +1,517 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	uploadRecursive   bool
+	uploadConcurrency int
+	uploadJSON        bool
+	uploadArchive     string
+	uploadProgress    bool
+	uploadEncrypt     bool
+	uploadPassword    string
+)
+
+var uploadCmd = &cobra.Command{
+	Use:   "upload [file...]",
+	Short: "Upload one or more files to the site",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   runUpload,
+}
+
+func init() {
+	uploadCmd.Flags().BoolVar(&uploadRecursive, "recursive", false, "Recurse into directories")
+	uploadCmd.Flags().IntVar(&uploadConcurrency, "concurrency", 1, "Number of files to upload in parallel")
+	uploadCmd.Flags().BoolVar(&uploadJSON, "json", false, "Print results as JSON")
+	uploadCmd.Flags().StringVar(&uploadArchive, "archive", "", "Pack the given paths into a single archive (zip or tar.gz) before uploading")
+	uploadCmd.Flags().BoolVar(&uploadProgress, "progress", false, "Show a running byte count per file while uploading")
+	uploadCmd.Flags().BoolVar(&uploadEncrypt, "encrypt", false, "Encrypt each file locally before uploading; the server only sees ciphertext")
+	uploadCmd.Flags().StringVar(&uploadPassword, "password", "", "Derive the encryption key from this password instead of generating one (implies --encrypt)")
+}
+
+// uploadResult is one row of the table/JSON output for a batch upload.
+type uploadResult struct {
+	Path  string `json:"path"`
+	URL   string `json:"url,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+func runUpload(cmd *cobra.Command, args []string) {
+	backend, err := resolveBackend()
+	if err != nil {
+		log.Fatalf("Upload failed: %v", err)
+	}
+
+	if uploadArchive != "" {
+		results := []uploadResult{backend.uploadArchivedPaths(args)}
+		printUploadResults(results)
+		return
+	}
+
+	if uploadProgress && uploadConcurrency > 1 {
+		fmt.Println("Warning: --progress prints one line per file and can't be shared across concurrent uploads; disabling it for this run.")
+		uploadProgress = false
+	}
+
+	paths, err := expandUploadPaths(args, uploadRecursive)
+	if err != nil {
+		log.Fatalf("Failed to resolve paths: %v", err)
+	}
+	if len(paths) == 0 {
+		log.Fatal("No files matched")
+	}
+
+	results := backend.uploadAll(paths, uploadConcurrency)
+	printUploadResults(results)
+}
+
+// expandUploadPaths turns the raw CLI args (which may be glob patterns,
+// plain files, or directories) into a flat list of files to upload.
+func expandUploadPaths(args []string, recursive bool) ([]string, error) {
+	var out []string
+	for _, arg := range args {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			matches = []string{arg}
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, err
+			}
+
+			if !info.IsDir() {
+				out = append(out, match)
+				continue
+			}
+
+			if !recursive {
+				return nil, fmt.Errorf("%s is a directory; pass --recursive to upload its contents", match)
+			}
+
+			err = filepath.WalkDir(match, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.IsDir() {
+					out = append(out, path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return out, nil
+}
+
+// uploadAll uploads every path, running at most concurrency uploads at
+// once via a simple semaphore-backed worker pool.
+func (b *Backend) uploadAll(paths []string, concurrency int) []uploadResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]uploadResult, len(paths))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, path := range paths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			url, err := b.uploadFile(path)
+			if err != nil {
+				results[i] = uploadResult{Path: path, Error: err.Error()}
+				return
+			}
+			results[i] = uploadResult{Path: path, URL: url}
+		}(i, path)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func printUploadResults(results []uploadResult) {
+	if uploadJSON {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to marshal results: %v", err)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Printf("FAIL\t%s\t%s\n", r.Path, r.Error)
+			continue
+		}
+		fmt.Printf("OK\t%s\t%s\n", r.Path, r.URL)
+		copyToClipboard(r.URL)
+	}
+}
+
+func (b *Backend) uploadFile(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var source io.Reader = file
+	var key []byte
+	if uploadEncrypt || uploadPassword != "" {
+		source, key, err = encryptUploadSource(file, uploadPassword)
+		if err != nil {
+			return "", err
+		}
+	}
+	if uploadProgress {
+		source = io.TeeReader(source, newProgressMeter(filePath))
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(part, source); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	if uploadProgress {
+		fmt.Println()
+	}
+
+	req, err := http.NewRequest("POST", b.uploadURL(), body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	url, err := b.sendUploadRequest(req)
+	if err != nil {
+		return "", err
+	}
+	if key != nil {
+		url = withKeyFragment(url, key)
+	}
+	return url, nil
+}
+
+// encryptUploadSource reads f fully, encrypts it, and returns a reader of
+// the base64-encoded ciphertext alongside the key used, so the result can
+// be uploaded as plain text and later recovered with `keirancli fetch`.
+func encryptUploadSource(f *os.File, password string) (io.Reader, []byte, error) {
+	key, err := resolveEncryptionKey(password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encoded, err := encryptBytes(data, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return strings.NewReader(encoded), key, nil
+}
+
+// uploadArchivedPaths packs args into a single zip or tar.gz archive. When
+// --encrypt/--password is set the archive must be sealed as a whole, so it
+// delegates to uploadEncryptedArchive; otherwise it streams straight into
+// the multipart upload body through io.Pipe rather than buffering the
+// whole archive in memory.
+func (b *Backend) uploadArchivedPaths(args []string) uploadResult {
+	name := "upload." + uploadArchive
+
+	if uploadEncrypt || uploadPassword != "" {
+		return b.uploadEncryptedArchive(args, name)
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	go func() {
+		part, err := writer.CreateFormFile("file", name)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		switch uploadArchive {
+		case "zip":
+			err = writeZipArchive(part, args)
+		case "tar.gz":
+			err = writeTarGzArchive(part, args)
+		default:
+			err = fmt.Errorf("unsupported --archive format %q (want zip or tar.gz)", uploadArchive)
+		}
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(writer.Close())
+	}()
+
+	var body io.Reader = pr
+	if uploadProgress {
+		body = io.TeeReader(pr, newProgressMeter(name))
+	}
+
+	req, err := http.NewRequest("POST", b.uploadURL(), body)
+	if err != nil {
+		return uploadResult{Path: name, Error: err.Error()}
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	url, err := b.sendUploadRequest(req)
+	if uploadProgress {
+		fmt.Println()
+	}
+	if err != nil {
+		return uploadResult{Path: name, Error: err.Error()}
+	}
+	return uploadResult{Path: name, URL: url}
+}
+
+// uploadEncryptedArchive packs args into an in-memory archive, encrypts it
+// as a whole (AES-GCM needs the full plaintext to seal, the same
+// trade-off encryptUploadSource makes for single files), and uploads the
+// result as a regular, non-streamed multipart body.
+func (b *Backend) uploadEncryptedArchive(args []string, name string) uploadResult {
+	archive := &bytes.Buffer{}
+	var err error
+	switch uploadArchive {
+	case "zip":
+		err = writeZipArchive(archive, args)
+	case "tar.gz":
+		err = writeTarGzArchive(archive, args)
+	default:
+		err = fmt.Errorf("unsupported --archive format %q (want zip or tar.gz)", uploadArchive)
+	}
+	if err != nil {
+		return uploadResult{Path: name, Error: err.Error()}
+	}
+
+	key, err := resolveEncryptionKey(uploadPassword)
+	if err != nil {
+		return uploadResult{Path: name, Error: err.Error()}
+	}
+	encoded, err := encryptBytes(archive.Bytes(), key)
+	if err != nil {
+		return uploadResult{Path: name, Error: err.Error()}
+	}
+
+	var source io.Reader = strings.NewReader(encoded)
+	if uploadProgress {
+		source = io.TeeReader(source, newProgressMeter(name))
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", name)
+	if err != nil {
+		return uploadResult{Path: name, Error: err.Error()}
+	}
+	if _, err := io.Copy(part, source); err != nil {
+		return uploadResult{Path: name, Error: err.Error()}
+	}
+	if err := writer.Close(); err != nil {
+		return uploadResult{Path: name, Error: err.Error()}
+	}
+	if uploadProgress {
+		fmt.Println()
+	}
+
+	req, err := http.NewRequest("POST", b.uploadURL(), body)
+	if err != nil {
+		return uploadResult{Path: name, Error: err.Error()}
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	url, err := b.sendUploadRequest(req)
+	if err != nil {
+		return uploadResult{Path: name, Error: err.Error()}
+	}
+	return uploadResult{Path: name, URL: withKeyFragment(url, key)}
+}
+
+// sendUploadRequest attaches auth, performs the request, and extracts the
+// uploaded URL + delete token from the JSON response. Shared by uploadFile
+// and uploadArchivedPaths.
+func (b *Backend) sendUploadRequest(req *http.Request) (string, error) {
+	b.setAuthHeader(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to upload file: %s", resp.Status)
+	}
+
+	var result map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+
+	imageUrl, exists := result[b.uploadURLField()]
+	if !exists {
+		return "", fmt.Errorf("%s not found in response", b.uploadURLField())
+	}
+
+	if err := recordToken(imageUrl, "upload", extractDeleteToken(result)); err != nil {
+		fmt.Printf("Warning: failed to save delete token: %v\n", err)
+	}
+
+	return imageUrl, nil
+}
+
+func writeZipArchive(w io.Writer, paths []string) error {
+	zw := zip.NewWriter(w)
+	err := walkArchivePaths(paths, func(rel string, f *os.File) error {
+		entry, err := zw.Create(rel)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(entry, f)
+		return err
+	})
+	if err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+func writeTarGzArchive(w io.Writer, paths []string) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	err := walkArchivePaths(paths, func(rel string, f *os.File) error {
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		tw.Close()
+		gw.Close()
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		gw.Close()
+		return err
+	}
+	return gw.Close()
+}
+
+// walkArchivePaths expands paths (recursing into directories) and calls
+// add for each regular file found. A plain file is stored under its base
+// name; a file inside a directory argument gets rel relative to that
+// directory's parent, so the archive keeps the directory's structure
+// under its own name.
+func walkArchivePaths(paths []string, add func(rel string, f *os.File) error) error {
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			if err := addArchiveFile(root, filepath.Base(root), add); err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(filepath.Dir(root), path)
+			if err != nil {
+				return err
+			}
+			return addArchiveFile(path, rel, add)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addArchiveFile(path, rel string, add func(rel string, f *os.File) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return add(rel, f)
+}
+
+// progressMeter prints a running byte count as data is copied through it,
+// used as the io.Writer half of an io.TeeReader-wrapped file upload.
+type progressMeter struct {
+	label string
+	total int64
+}
+
+func newProgressMeter(label string) *progressMeter {
+	return &progressMeter{label: label}
+}
+
+func (p *progressMeter) Write(b []byte) (int, error) {
+	p.total += int64(len(b))
+	fmt.Printf("\r%s: %d bytes", p.label, p.total)
+	return len(b), nil
+}